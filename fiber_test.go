@@ -2,14 +2,23 @@ package learn_fiber
 
 import (
 	"bytes"
+	"crypto/rand"
 	_ "embed"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httptest"
+	"net/url"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
@@ -226,3 +235,902 @@ func TestRouteGroup(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "Hello World!", string(result))
 }
+
+type NegotiatedUser struct {
+	Name  string `json:"name" xml:"name"`
+	Email string `json:"email" xml:"email"`
+}
+
+// respondNegotiated renders payload as JSON, XML, or HTML depending on what
+// the client can accept, falling back to 406 when nothing matches.
+func respondNegotiated(ctx *fiber.Ctx, payload NegotiatedUser) error {
+	if ctx.AcceptsCharsets("utf-8") == "" {
+		return ctx.SendStatus(fiber.StatusNotAcceptable)
+	}
+	if ctx.AcceptsEncodings("gzip", "identity") == "" {
+		return ctx.SendStatus(fiber.StatusNotAcceptable)
+	}
+
+	if language := ctx.AcceptsLanguages("en", "id"); language != "" {
+		ctx.Set(fiber.HeaderContentLanguage, language)
+	}
+
+	switch ctx.Accepts("json", "xml", "html") {
+	case "json":
+		return ctx.JSON(payload)
+	case "xml":
+		ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationXML)
+		return xml.NewEncoder(ctx).Encode(payload)
+	case "html":
+		ctx.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return ctx.SendString("<ul><li>" + payload.Name + "</li><li>" + payload.Email + "</li></ul>")
+	default:
+		return ctx.SendStatus(fiber.StatusNotAcceptable)
+	}
+}
+
+func TestContentNegotiationJson(t *testing.T) {
+	app.Get("/negotiated", func(ctx *fiber.Ctx) error {
+		return respondNegotiated(ctx, NegotiatedUser{Name: "Raihanhori", Email: "raihanki02@gmail.com"})
+	})
+
+	request, _ := http.NewRequest(http.MethodGet, "/negotiated", nil)
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.MIMEApplicationJSON, response.Header.Get(fiber.HeaderContentType))
+
+	result, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"Raihanhori","email":"raihanki02@gmail.com"}`, string(result))
+}
+
+func TestContentNegotiationXml(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "/negotiated", nil)
+	request.Header.Set("Accept", "application/xml")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.MIMEApplicationXML, response.Header.Get(fiber.HeaderContentType))
+
+	result, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, "<NegotiatedUser><name>Raihanhori</name><email>raihanki02@gmail.com</email></NegotiatedUser>", string(result))
+}
+
+func TestContentNegotiationHtml(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "/negotiated", nil)
+	request.Header.Set("Accept", "text/html")
+	request.Header.Set("Accept-Language", "id")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.MIMETextHTMLCharsetUTF8, response.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, "id", response.Header.Get(fiber.HeaderContentLanguage))
+
+	result, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, "<ul><li>Raihanhori</li><li>raihanki02@gmail.com</li></ul>", string(result))
+}
+
+func TestContentNegotiationNotAcceptable(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "/negotiated", nil)
+	request.Header.Set("Accept", "application/pdf")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusNotAcceptable, response.StatusCode)
+}
+
+func TestContentNegotiationUnacceptableCharset(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "/negotiated", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Accept-Charset", "iso-8859-1")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusNotAcceptable, response.StatusCode)
+}
+
+func TestContentNegotiationUnacceptableEncoding(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "/negotiated", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Accept-Encoding", "br")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusNotAcceptable, response.StatusCode)
+}
+
+// sessionCredentials is a stand-in user database for the session examples below.
+var sessionCredentials = map[string]string{
+	"raihan@test.com": "password",
+}
+
+// SessionStore keeps track of which session ID belongs to which user. It is
+// safe for concurrent use since app.Test may be exercised from parallel tests.
+type SessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]string // sessionId -> email
+}
+
+func newSessionStore() *SessionStore {
+	return &SessionStore{sessions: make(map[string]string)}
+}
+
+func (s *SessionStore) create(email string) string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	sessionId := hex.EncodeToString(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[sessionId] = email
+
+	return sessionId
+}
+
+func (s *SessionStore) get(sessionId string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	email, ok := s.sessions[sessionId]
+	return email, ok
+}
+
+func (s *SessionStore) delete(sessionId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionId)
+}
+
+var sessionStore = newSessionStore()
+
+func TestSessionLoginFlow(t *testing.T) {
+	app.Post("/session/login", func(ctx *fiber.Ctx) error {
+		loginRequest := new(LoginRequest)
+		if err := ctx.BodyParser(loginRequest); err != nil {
+			return err
+		}
+
+		password, ok := sessionCredentials[loginRequest.Email]
+		if !ok || password != loginRequest.Password {
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+
+		sessionId := sessionStore.create(loginRequest.Email)
+		ctx.Cookie(&fiber.Cookie{
+			Name:     "session_id",
+			Value:    sessionId,
+			HTTPOnly: true,
+			Secure:   true,
+			SameSite: "Lax",
+		})
+
+		return ctx.SendStatus(fiber.StatusOK)
+	})
+
+	app.Get("/session/me", func(ctx *fiber.Ctx) error {
+		email, ok := sessionStore.get(ctx.Cookies("session_id"))
+		if !ok {
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+
+		return ctx.JSON(fiber.Map{"email": email})
+	})
+
+	app.Post("/session/logout", func(ctx *fiber.Ctx) error {
+		sessionStore.delete(ctx.Cookies("session_id"))
+		ctx.ClearCookie("session_id")
+
+		return ctx.SendStatus(fiber.StatusOK)
+	})
+
+	wrongPasswordBody := strings.NewReader(`{"email":"raihan@test.com","password":"wrong-password"}`)
+	wrongPasswordRequest, _ := http.NewRequest(http.MethodPost, "/session/login", wrongPasswordBody)
+	wrongPasswordRequest.Header.Set("Content-Type", "application/json")
+
+	wrongPasswordResponse, err := app.Test(wrongPasswordRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, wrongPasswordResponse.StatusCode)
+
+	noCookieRequest, _ := http.NewRequest(http.MethodGet, "/session/me", nil)
+	noCookieResponse, err := app.Test(noCookieRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, noCookieResponse.StatusCode)
+
+	jar, err := cookiejar.New(nil)
+	assert.Nil(t, err)
+	baseUrl, err := url.Parse("https://example.com") // https so the jar keeps the Secure session cookie
+	assert.Nil(t, err)
+
+	loginBody := strings.NewReader(`{"email":"raihan@test.com","password":"password"}`)
+	loginRequest, _ := http.NewRequest(http.MethodPost, "/session/login", loginBody)
+	loginRequest.Header.Set("Content-Type", "application/json")
+
+	loginResponse, err := app.Test(loginRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusOK, loginResponse.StatusCode)
+	jar.SetCookies(baseUrl, loginResponse.Cookies())
+
+	meRequest, _ := http.NewRequest(http.MethodGet, "/session/me", nil)
+	for _, cookie := range jar.Cookies(baseUrl) {
+		meRequest.AddCookie(cookie)
+	}
+
+	meResponse, err := app.Test(meRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusOK, meResponse.StatusCode)
+
+	result, err := io.ReadAll(meResponse.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"email":"raihan@test.com"}`, string(result))
+
+	logoutRequest, _ := http.NewRequest(http.MethodPost, "/session/logout", nil)
+	for _, cookie := range jar.Cookies(baseUrl) {
+		logoutRequest.AddCookie(cookie)
+	}
+
+	logoutResponse, err := app.Test(logoutRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusOK, logoutResponse.StatusCode)
+	jar.SetCookies(baseUrl, logoutResponse.Cookies())
+
+	afterLogoutRequest, _ := http.NewRequest(http.MethodGet, "/session/me", nil)
+	for _, cookie := range jar.Cookies(baseUrl) {
+		afterLogoutRequest.AddCookie(cookie)
+	}
+
+	afterLogoutResponse, err := app.Test(afterLogoutRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, afterLogoutResponse.StatusCode)
+}
+
+type WebmentionStatus string
+
+const (
+	WebmentionPending  WebmentionStatus = "pending"
+	WebmentionApproved WebmentionStatus = "approved"
+	WebmentionRejected WebmentionStatus = "rejected"
+)
+
+type Webmention struct {
+	ID     string
+	Source string
+	Target string
+	Status WebmentionStatus
+}
+
+// WebmentionStore persists submitted mentions and their verification status.
+// Create and Get return value copies rather than the shared pointer so
+// callers never read a field that UpdateStatus is concurrently mutating.
+type WebmentionStore interface {
+	Create(source, target string) Webmention
+	Get(id string) (Webmention, bool)
+	UpdateStatus(id string, status WebmentionStatus)
+}
+
+type InMemoryWebmentionStore struct {
+	mu          sync.Mutex
+	webmentions map[string]*Webmention
+}
+
+func newInMemoryWebmentionStore() *InMemoryWebmentionStore {
+	return &InMemoryWebmentionStore{webmentions: make(map[string]*Webmention)}
+}
+
+func (s *InMemoryWebmentionStore) Create(source, target string) Webmention {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+
+	mention := &Webmention{
+		ID:     hex.EncodeToString(buf),
+		Source: source,
+		Target: target,
+		Status: WebmentionPending,
+	}
+
+	s.mu.Lock()
+	s.webmentions[mention.ID] = mention
+	s.mu.Unlock()
+
+	return *mention
+}
+
+func (s *InMemoryWebmentionStore) Get(id string) (Webmention, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mention, ok := s.webmentions[id]
+	if !ok {
+		return Webmention{}, false
+	}
+
+	return *mention, ok
+}
+
+func (s *InMemoryWebmentionStore) UpdateStatus(id string, status WebmentionStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if mention, ok := s.webmentions[id]; ok {
+		mention.Status = status
+	}
+}
+
+// WebmentionVerifier checks whether source actually links to target. It is an
+// interface so tests can swap in a fake instead of making real HTTP calls.
+type WebmentionVerifier interface {
+	Verify(source, target string) bool
+}
+
+type httpWebmentionVerifier struct{}
+
+func (httpWebmentionVerifier) Verify(source, target string) bool {
+	response, err := http.Get(source)
+	if err != nil {
+		return false
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(string(body), target)
+}
+
+var webmentionStore WebmentionStore = newInMemoryWebmentionStore()
+var webmentionVerifier WebmentionVerifier = httpWebmentionVerifier{}
+
+func TestWebmentionReceiver(t *testing.T) {
+	app.Post("/webmention", func(ctx *fiber.Ctx) error {
+		source := ctx.FormValue("source")
+		target := ctx.FormValue("target")
+
+		if source == "" || target == "" {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "source and target are required"})
+		}
+
+		sourceUrl, err := url.ParseRequestURI(source)
+		if err != nil || !sourceUrl.IsAbs() {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "source must be an absolute URL"})
+		}
+
+		targetUrl, err := url.ParseRequestURI(target)
+		if err != nil || !targetUrl.IsAbs() {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "target must be an absolute URL"})
+		}
+
+		if targetUrl.Host != ctx.Hostname() {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "target does not belong to this host"})
+		}
+
+		mention := webmentionStore.Create(source, target)
+
+		go func() {
+			status := WebmentionRejected
+			if webmentionVerifier.Verify(mention.Source, mention.Target) {
+				status = WebmentionApproved
+			}
+			webmentionStore.UpdateStatus(mention.ID, status)
+		}()
+
+		ctx.Set(fiber.HeaderLocation, "/webmention/"+mention.ID)
+		return ctx.Status(fiber.StatusAccepted).JSON(fiber.Map{"id": mention.ID, "status": mention.Status})
+	})
+
+	app.Get("/webmention/:id", func(ctx *fiber.Ctx) error {
+		mention, ok := webmentionStore.Get(ctx.Params("id"))
+		if !ok {
+			return ctx.SendStatus(fiber.StatusNotFound)
+		}
+
+		return ctx.JSON(fiber.Map{"id": mention.ID, "status": mention.Status})
+	})
+
+	t.Run("valid submission is accepted and later approved", func(t *testing.T) {
+		webmentionVerifier = fakeWebmentionVerifier{approve: true}
+
+		form := url.Values{}
+		form.Set("source", "https://example.com/source-post")
+		form.Set("target", "https://example.com/target-post")
+
+		request, _ := http.NewRequest(http.MethodPost, "/webmention", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.Host = "example.com"
+
+		response, err := app.Test(request)
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusAccepted, response.StatusCode)
+		assert.NotEmpty(t, response.Header.Get(fiber.HeaderLocation))
+
+		var body map[string]string
+		assert.Nil(t, json.NewDecoder(response.Body).Decode(&body))
+
+		statusBody, statusResponse := awaitWebmentionStatus(t, body["id"], WebmentionApproved)
+		assert.Equal(t, fiber.StatusOK, statusResponse.StatusCode)
+		assert.Equal(t, string(WebmentionApproved), statusBody["status"])
+	})
+
+	t.Run("rejected verification is reflected in the status endpoint", func(t *testing.T) {
+		webmentionVerifier = fakeWebmentionVerifier{approve: false}
+
+		form := url.Values{}
+		form.Set("source", "https://example.com/spammy-post")
+		form.Set("target", "https://example.com/target-post")
+
+		request, _ := http.NewRequest(http.MethodPost, "/webmention", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.Host = "example.com"
+
+		response, err := app.Test(request)
+		assert.Nil(t, err)
+
+		var body map[string]string
+		assert.Nil(t, json.NewDecoder(response.Body).Decode(&body))
+
+		statusBody, _ := awaitWebmentionStatus(t, body["id"], WebmentionRejected)
+		assert.Equal(t, string(WebmentionRejected), statusBody["status"])
+	})
+
+	t.Run("missing fields are rejected", func(t *testing.T) {
+		form := url.Values{}
+		form.Set("source", "https://example.com/source-post")
+
+		request, _ := http.NewRequest(http.MethodPost, "/webmention", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		response, err := app.Test(request)
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, response.StatusCode)
+
+		var body map[string]string
+		assert.Nil(t, json.NewDecoder(response.Body).Decode(&body))
+		assert.Equal(t, "source and target are required", body["error"])
+	})
+
+	t.Run("non-absolute source is rejected", func(t *testing.T) {
+		form := url.Values{}
+		form.Set("source", "/source-post")
+		form.Set("target", "https://example.com/target-post")
+
+		request, _ := http.NewRequest(http.MethodPost, "/webmention", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.Host = "example.com"
+
+		response, err := app.Test(request)
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, response.StatusCode)
+
+		var body map[string]string
+		assert.Nil(t, json.NewDecoder(response.Body).Decode(&body))
+		assert.Equal(t, "source must be an absolute URL", body["error"])
+	})
+
+	t.Run("non-absolute target is rejected", func(t *testing.T) {
+		form := url.Values{}
+		form.Set("source", "https://example.com/source-post")
+		form.Set("target", "/target-post")
+
+		request, _ := http.NewRequest(http.MethodPost, "/webmention", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.Host = "example.com"
+
+		response, err := app.Test(request)
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, response.StatusCode)
+
+		var body map[string]string
+		assert.Nil(t, json.NewDecoder(response.Body).Decode(&body))
+		assert.Equal(t, "target must be an absolute URL", body["error"])
+	})
+
+	t.Run("target on a different host is rejected", func(t *testing.T) {
+		form := url.Values{}
+		form.Set("source", "https://example.com/source-post")
+		form.Set("target", "https://other.example.com/target-post")
+
+		request, _ := http.NewRequest(http.MethodPost, "/webmention", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.Host = "example.com"
+
+		response, err := app.Test(request)
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, response.StatusCode)
+
+		var body map[string]string
+		assert.Nil(t, json.NewDecoder(response.Body).Decode(&body))
+		assert.Equal(t, "target does not belong to this host", body["error"])
+	})
+}
+
+// awaitWebmentionStatus polls the status endpoint until the mention reaches
+// want or the timeout elapses, so tests don't race the background
+// verification goroutine with a fixed sleep.
+func awaitWebmentionStatus(t *testing.T, id string, want WebmentionStatus) (map[string]string, *http.Response) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		statusRequest, _ := http.NewRequest(http.MethodGet, "/webmention/"+id, nil)
+		statusResponse, err := app.Test(statusRequest)
+		assert.Nil(t, err)
+
+		var statusBody map[string]string
+		assert.Nil(t, json.NewDecoder(statusResponse.Body).Decode(&statusBody))
+
+		if statusBody["status"] == string(want) || time.Now().After(deadline) {
+			return statusBody, statusResponse
+		}
+
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+type fakeWebmentionVerifier struct {
+	approve bool
+}
+
+func (f fakeWebmentionVerifier) Verify(source, target string) bool {
+	return f.approve
+}
+
+type Feed struct {
+	Title   string `json:"title"`
+	XmlUrl  string `json:"xmlUrl"`
+	HtmlUrl string `json:"htmlUrl"`
+}
+
+// FeedStore holds the feeds that make up the blogroll.
+type FeedStore interface {
+	Add(feed Feed)
+	All() []Feed
+}
+
+type InMemoryFeedStore struct {
+	mu    sync.Mutex
+	feeds []Feed
+}
+
+func newInMemoryFeedStore() *InMemoryFeedStore {
+	return &InMemoryFeedStore{}
+}
+
+func (s *InMemoryFeedStore) Add(feed Feed) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.feeds = append(s.feeds, feed)
+}
+
+func (s *InMemoryFeedStore) All() []Feed {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feeds := make([]Feed, len(s.feeds))
+	copy(feeds, s.feeds)
+
+	sort.Slice(feeds, func(i, j int) bool {
+		return feeds[i].Title < feeds[j].Title
+	})
+
+	return feeds
+}
+
+type opmlOutline struct {
+	Text    string `xml:"text,attr"`
+	Title   string `xml:"title,attr"`
+	Type    string `xml:"type,attr"`
+	XmlUrl  string `xml:"xmlUrl,attr"`
+	HtmlUrl string `xml:"htmlUrl,attr"`
+}
+
+type opmlBody struct {
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+type opmlHead struct {
+	Title string `xml:"title"`
+}
+
+type opmlDocument struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    opmlHead `xml:"head"`
+	Body    opmlBody `xml:"body"`
+}
+
+func TestBlogrollOpmlExport(t *testing.T) {
+	// A dedicated app and store keep this test idempotent across repeat
+	// runs (e.g. -count=N) instead of accumulating feeds on the shared app.
+	blogrollApp := fiber.New()
+	feedStore := newInMemoryFeedStore()
+
+	blogrollApp.Post("/blogroll", func(ctx *fiber.Ctx) error {
+		feed := new(Feed)
+		if err := ctx.BodyParser(feed); err != nil {
+			return err
+		}
+
+		feedStore.Add(*feed)
+		return ctx.SendStatus(fiber.StatusCreated)
+	})
+
+	blogrollApp.Get("/blogroll.opml", func(ctx *fiber.Ctx) error {
+		document := opmlDocument{
+			Version: "2.0",
+			Head:    opmlHead{Title: "Blogroll"},
+		}
+
+		for _, feed := range feedStore.All() {
+			document.Body.Outlines = append(document.Body.Outlines, opmlOutline{
+				Text:    feed.Title,
+				Title:   feed.Title,
+				Type:    "rss",
+				XmlUrl:  feed.XmlUrl,
+				HtmlUrl: feed.HtmlUrl,
+			})
+		}
+
+		output, err := xml.Marshal(document)
+		if err != nil {
+			return err
+		}
+
+		ctx.Set(fiber.HeaderContentType, "text/x-opml")
+		return ctx.Send(output)
+	})
+
+	feeds := []Feed{
+		{Title: "Zeta Blog", XmlUrl: "https://zeta.example.com/feed.xml", HtmlUrl: "https://zeta.example.com"},
+		{Title: "Alpha Blog", XmlUrl: "https://alpha.example.com/feed.xml", HtmlUrl: "https://alpha.example.com"},
+		{Title: "Midway Blog", XmlUrl: "https://midway.example.com/feed.xml", HtmlUrl: "https://midway.example.com"},
+	}
+
+	for _, feed := range feeds {
+		payload, err := json.Marshal(feed)
+		assert.Nil(t, err)
+
+		request, _ := http.NewRequest(http.MethodPost, "/blogroll", bytes.NewReader(payload))
+		request.Header.Set("Content-Type", "application/json")
+
+		response, err := blogrollApp.Test(request)
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusCreated, response.StatusCode)
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "/blogroll.opml", nil)
+	response, err := blogrollApp.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, "text/x-opml", response.Header.Get(fiber.HeaderContentType))
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	document := new(opmlDocument)
+	assert.Nil(t, xml.Unmarshal(body, document))
+	assert.Len(t, document.Body.Outlines, 3)
+	assert.Equal(t, "Alpha Blog", document.Body.Outlines[0].Title)
+	assert.Equal(t, "Midway Blog", document.Body.Outlines[1].Title)
+	assert.Equal(t, "Zeta Blog", document.Body.Outlines[2].Title)
+}
+
+type CurlLoggerOptions struct {
+	Writer          io.Writer
+	RedactedHeaders []string
+}
+
+// CurlLoggerMiddleware writes an equivalent curl command for every incoming
+// request to opts.Writer, redacting any header named in opts.RedactedHeaders.
+func CurlLoggerMiddleware(opts CurlLoggerOptions) fiber.Handler {
+	redacted := make(map[string]bool, len(opts.RedactedHeaders))
+	for _, name := range opts.RedactedHeaders {
+		redacted[strings.ToLower(name)] = true
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		request := ctx.Request()
+
+		var line strings.Builder
+		fmt.Fprintf(&line, "curl -X %s %s", request.Header.Method(), shellEscape(ctx.BaseURL()+ctx.OriginalURL()))
+
+		request.Header.VisitAll(func(key, value []byte) {
+			name, headerValue := string(key), string(value)
+			if redacted[strings.ToLower(name)] {
+				headerValue = "REDACTED"
+			}
+			fmt.Fprintf(&line, " -H %s", shellEscape(name+": "+headerValue))
+		})
+
+		if strings.HasPrefix(string(request.Header.ContentType()), fiber.MIMEMultipartForm) {
+			if form, err := ctx.MultipartForm(); err == nil {
+				for field, files := range form.File {
+					for _, file := range files {
+						fmt.Fprintf(&line, " -F %s", shellEscape(field+"=@"+file.Filename))
+					}
+				}
+			}
+		} else if len(ctx.Body()) > 0 {
+			line.WriteString(" --data-binary @-")
+		}
+
+		fmt.Fprintln(opts.Writer, line.String())
+
+		return ctx.Next()
+	}
+}
+
+func shellEscape(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func TestCurlLoggerMiddleware(t *testing.T) {
+	// A dedicated app avoids re-registering the middleware on the shared app
+	// on every run, which would otherwise keep routing to a stale closure
+	// over a previous run's buffer (e.g. under -count=N).
+	curlApp := fiber.New()
+	var buffer bytes.Buffer
+
+	curlApp.Use("/curl-logged", CurlLoggerMiddleware(CurlLoggerOptions{
+		Writer:          &buffer,
+		RedactedHeaders: []string{"Authorization", "Cookie"},
+	}))
+
+	ok := func(ctx *fiber.Ctx) error { return ctx.SendString("ok") }
+	curlApp.Get("/curl-logged/search", ok)
+	curlApp.Post("/curl-logged/register", ok)
+	curlApp.Post("/curl-logged/upload", ok)
+
+	t.Run("GET with query params redacts authorization", func(t *testing.T) {
+		buffer.Reset()
+
+		request, _ := http.NewRequest(http.MethodGet, "/curl-logged/search?q=fiber", nil)
+		request.Header.Set("Authorization", "Bearer secret-token")
+
+		_, err := curlApp.Test(request)
+		assert.Nil(t, err)
+		assert.Contains(t, buffer.String(), "-X GET")
+		assert.Contains(t, buffer.String(), "-H 'Authorization: REDACTED'")
+		assert.NotContains(t, buffer.String(), "secret-token")
+	})
+
+	t.Run("POST JSON body", func(t *testing.T) {
+		buffer.Reset()
+
+		body := strings.NewReader(`{"name":"raihanhori"}`)
+		request, _ := http.NewRequest(http.MethodPost, "/curl-logged/register", body)
+		request.Header.Set("Content-Type", "application/json")
+
+		_, err := curlApp.Test(request)
+		assert.Nil(t, err)
+		assert.Contains(t, buffer.String(), "-X POST")
+		assert.Contains(t, buffer.String(), "-H 'Content-Type: application/json'")
+		assert.Contains(t, buffer.String(), "--data-binary @-")
+	})
+
+	t.Run("multipart upload", func(t *testing.T) {
+		buffer.Reset()
+
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		file, _ := writer.CreateFormFile("file", "contoh.txt")
+		file.Write(contohFile)
+		writer.Close()
+
+		request, _ := http.NewRequest(http.MethodPost, "/curl-logged/upload", body)
+		request.Header.Set("Content-Type", writer.FormDataContentType())
+
+		_, err := curlApp.Test(request)
+		assert.Nil(t, err)
+		assert.Contains(t, buffer.String(), "-X POST")
+		assert.Contains(t, buffer.String(), "-F 'file=@contoh.txt'")
+	})
+}
+
+// AuthUser is the context value installed by AuthMiddleware once a bearer
+// token has been resolved.
+type AuthUser struct {
+	Name        string
+	Permissions []string
+}
+
+func (u AuthUser) hasPermission(permission string) bool {
+	for _, p := range u.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// authTokens is a stand-in token database for the authorization examples below.
+var authTokens = map[string]AuthUser{
+	"token-owner":    {Name: "raihanhori"},
+	"token-admin":    {Name: "admin", Permissions: []string{"user.show", "admin.list"}},
+	"token-stranger": {Name: "stranger"},
+}
+
+func AuthMiddleware(ctx *fiber.Ctx) error {
+	token := strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+
+	user, ok := authTokens[token]
+	if !ok {
+		return ctx.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	ctx.Locals("user", user)
+	return ctx.Next()
+}
+
+// RequirePermission allows the request through when the authenticated user
+// holds perm globally, or when ownerCheck reports the user owns the resource.
+func RequirePermission(perm string, ownerCheck func(ctx *fiber.Ctx) bool) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		user, ok := ctx.Locals("user").(AuthUser)
+		if !ok {
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+
+		if user.hasPermission(perm) || ownerCheck(ctx) {
+			return ctx.Next()
+		}
+
+		return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+	}
+}
+
+func isOwner(ctx *fiber.Ctx) bool {
+	user, ok := ctx.Locals("user").(AuthUser)
+	return ok && ctx.Params("username") == user.Name
+}
+
+func TestPermissionGuard(t *testing.T) {
+	// A dedicated app avoids reusing the "/api" prefix that TestRouteGroup
+	// already serves unauthenticated on the shared app, which would
+	// otherwise make AuthMiddleware intercept those routes too whenever
+	// this test happens to register first (e.g. under -shuffle=on).
+	guardApp := fiber.New()
+	api := guardApp.Group("/api", AuthMiddleware)
+
+	api.Get("/users/:username", RequirePermission("user.show", isOwner), func(ctx *fiber.Ctx) error {
+		return ctx.SendString("profile of " + ctx.Params("username"))
+	})
+
+	api.Put("/users/:username/edit", RequirePermission("user.edit", isOwner), func(ctx *fiber.Ctx) error {
+		return ctx.SendString("edited " + ctx.Params("username"))
+	})
+
+	api.Get("/admin/users", RequirePermission("admin.list", func(ctx *fiber.Ctx) bool { return false }), func(ctx *fiber.Ctx) error {
+		return ctx.SendString("all users")
+	})
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		wantStatus int
+	}{
+		{"owner can read own profile", http.MethodGet, "/api/users/raihanhori", "token-owner", fiber.StatusOK},
+		{"admin can read any profile via user.show", http.MethodGet, "/api/users/raihanhori", "token-admin", fiber.StatusOK},
+		{"stranger cannot read another profile", http.MethodGet, "/api/users/raihanhori", "token-stranger", fiber.StatusForbidden},
+		{"owner can edit own profile", http.MethodPut, "/api/users/raihanhori/edit", "token-owner", fiber.StatusOK},
+		{"admin cannot edit another profile", http.MethodPut, "/api/users/raihanhori/edit", "token-admin", fiber.StatusForbidden},
+		{"stranger cannot edit another profile", http.MethodPut, "/api/users/raihanhori/edit", "token-stranger", fiber.StatusForbidden},
+		{"admin can list users", http.MethodGet, "/api/admin/users", "token-admin", fiber.StatusOK},
+		{"owner cannot list users", http.MethodGet, "/api/admin/users", "token-owner", fiber.StatusForbidden},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			request, _ := http.NewRequest(c.method, c.path, nil)
+			request.Header.Set("Authorization", "Bearer "+c.token)
+
+			response, err := guardApp.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, c.wantStatus, response.StatusCode)
+		})
+	}
+}